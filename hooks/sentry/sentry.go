@@ -0,0 +1,51 @@
+// Package sentry ships Error-and-above log entries to Sentry.
+package sentry
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/ygj201011/logger"
+)
+
+// Hook forwards Panic/Fatal/Error level entries to the active sentry-go client.
+// Callers are expected to have already called sentry.Init elsewhere; the hook
+// only captures events, it doesn't own the client's lifecycle.
+type Hook struct{}
+
+// New returns a Hook ready to be passed to logger.RegisterHook.
+func New() *Hook {
+	return &Hook{}
+}
+
+func (h *Hook) Levels() []logger.Level {
+	return []logger.Level{logger.PanicLevel, logger.FatalLevel, logger.ErrorLevel}
+}
+
+func (h *Hook) Fire(entry logger.HookEntry) error {
+	event := sentry.NewEvent()
+	event.Message = entry.Message
+	event.Level = sentryLevel(entry.Level)
+	event.Timestamp = entry.Timestamp
+	if entry.Prefix != "" {
+		event.Logger = entry.Prefix
+	}
+	if len(entry.Fields) > 0 {
+		extra := make(sentry.Context, len(entry.Fields))
+		for k, v := range entry.Fields {
+			extra[k] = v
+		}
+		event.Contexts = map[string]sentry.Context{"extra": extra}
+	}
+	sentry.CaptureEvent(event)
+	return nil
+}
+
+func sentryLevel(level logger.Level) sentry.Level {
+	switch level {
+	case logger.PanicLevel, logger.FatalLevel:
+		return sentry.LevelFatal
+	case logger.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelWarning
+	}
+}
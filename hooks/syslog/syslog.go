@@ -0,0 +1,57 @@
+//go:build !windows
+
+// Package syslog ships log entries to the local syslog daemon. log/syslog is
+// Unix-only, hence the build tag.
+package syslog
+
+import (
+	"log/syslog"
+
+	"github.com/ygj201011/logger"
+)
+
+// Hook forwards entries to the local syslog daemon via log/syslog.
+type Hook struct {
+	writer *syslog.Writer
+}
+
+// New dials the local syslog daemon under tag and returns a Hook ready to be
+// passed to logger.RegisterHook.
+func New(tag string) (*Hook, error) {
+	w, err := syslog.New(syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &Hook{writer: w}, nil
+}
+
+func (h *Hook) Levels() []logger.Level {
+	return []logger.Level{
+		logger.PanicLevel,
+		logger.FatalLevel,
+		logger.ErrorLevel,
+		logger.WarnLevel,
+		logger.InfoLevel,
+		logger.DebugLevel,
+		logger.TraceLevel,
+	}
+}
+
+func (h *Hook) Fire(entry logger.HookEntry) error {
+	msg := entry.Message
+	if entry.Prefix != "" {
+		msg = entry.Prefix + ": " + msg
+	}
+	switch entry.Level {
+	case logger.PanicLevel, logger.FatalLevel:
+		return h.writer.Crit(msg)
+	case logger.ErrorLevel:
+		return h.writer.Err(msg)
+	case logger.WarnLevel:
+		return h.writer.Warning(msg)
+	case logger.DebugLevel, logger.TraceLevel:
+		return h.writer.Debug(msg)
+	default:
+		return h.writer.Info(msg)
+	}
+}
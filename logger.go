@@ -1,12 +1,17 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
-	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	zaplogfmt "github.com/jsternberg/zap-logfmt"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -46,8 +51,30 @@ type Logger interface {
 	WithFields(fields Fields) Logger
 	Fields() Fields
 
+	// WithCallerSkip returns a derived Logger that skips n extra stack frames
+	// before reporting the caller, for wrapper libraries that re-export this
+	// package's functions and would otherwise report their own call site.
+	WithCallerSkip(n int) Logger
+
+	WithContext(ctx context.Context) Logger
+	TraceCtx(ctx context.Context, args ...interface{})
+	DebugCtx(ctx context.Context, args ...interface{})
+	InfoCtx(ctx context.Context, args ...interface{})
+	WarnCtx(ctx context.Context, args ...interface{})
+	ErrorCtx(ctx context.Context, args ...interface{})
+
 	SetLevel(level Level)
 	GetLevel() Level
+
+	IsLevelEnabled(level Level) bool
+	IsTraceEnabled() bool
+	IsDebugEnabled() bool
+	IsInfoEnabled() bool
+	IsWarnEnabled() bool
+	IsErrorEnabled() bool
+
+	Log(level Level, args ...interface{})
+	Logf(level Level, format string, args ...interface{})
 }
 
 type Loggable interface {
@@ -94,6 +121,50 @@ const (
 	TraceLevel
 )
 
+// levelToZap translates our own Level scale to the zapcore.Level scale used by the
+// underlying core. zapcore has no Trace concept, so Trace is folded into Debug and
+// distinguished at emit time by comparing against GetLevel() instead.
+func levelToZap(level Level) zapcore.Level {
+	switch level {
+	case PanicLevel:
+		return zapcore.PanicLevel
+	case FatalLevel:
+		return zapcore.FatalLevel
+	case ErrorLevel:
+		return zapcore.ErrorLevel
+	case WarnLevel:
+		return zapcore.WarnLevel
+	case InfoLevel:
+		return zapcore.InfoLevel
+	case DebugLevel, TraceLevel:
+		return zapcore.DebugLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// zapToLevel is the approximate reverse of levelToZap, used to label hook entries.
+// zapcore.DebugLevel is ambiguous (both DebugLevel and TraceLevel map to it) so it
+// is reported as DebugLevel, the more common of the two.
+func zapToLevel(level zapcore.Level) Level {
+	switch level {
+	case zapcore.PanicLevel:
+		return PanicLevel
+	case zapcore.FatalLevel:
+		return FatalLevel
+	case zapcore.ErrorLevel:
+		return ErrorLevel
+	case zapcore.WarnLevel:
+		return WarnLevel
+	case zapcore.InfoLevel:
+		return InfoLevel
+	case zapcore.DebugLevel:
+		return DebugLevel
+	default:
+		return InfoLevel
+	}
+}
+
 func AddFieldsFrom(logger Logger, values ...interface{}) Logger {
 	for _, value := range values {
 		switch v := value.(type) {
@@ -115,176 +186,377 @@ var (
 
 type MyLogger struct {
 	log      *zap.Logger
-	level    Level
+	atom     zap.AtomicLevel
+	level    *int32
+	override *int32
+	parent   *MyLogger
 	prefix   []string
 	fields   Fields
-	codeLine bool
+	noFatals *int32
+	noPanics *int32
 }
 
-func (l MyLogger) Print(args ...interface{}) {
-	if isInit {
-		l.log.Sugar().Info(args...)
-	} else {
-		fmt.Println(args...)
+// noOverride marks override as "unset" so GetLevel falls through to the parent
+// chain; it's outside the valid Level range so it can never collide with a real
+// level.
+const noOverride int32 = -1
+
+// derive returns a copy of l wired up as a child in the level-override chain: a
+// fresh, unset override plus a pointer back to l, and independent noFatals/noPanics
+// seeded from l's current values. Until this copy's own SetLevel is called, its
+// level keeps following whatever l (and l's ancestors) do; once called, only this
+// branch is affected, matching zwrap's ForceLevel idea. noFatals/noPanics are
+// copies: later NoFatals/NoPanics calls on l do not reach this copy.
+func (l MyLogger) derive() MyLogger {
+	parent := l
+	override := new(int32)
+	atomic.StoreInt32(override, noOverride)
+	noFatals := new(int32)
+	if l.noFatalsEnabled() {
+		atomic.StoreInt32(noFatals, 1)
+	}
+	noPanics := new(int32)
+	if l.noPanicsEnabled() {
+		atomic.StoreInt32(noPanics, 1)
+	}
+	return MyLogger{
+		log:      l.log,
+		atom:     l.atom,
+		level:    l.level,
+		override: override,
+		parent:   &parent,
+		prefix:   l.prefix,
+		fields:   l.fields,
+		noFatals: noFatals,
+		noPanics: noPanics,
 	}
 }
-func (l MyLogger) addCodeInfo(format string) (r string) {
-	if l.codeLine {
-		r = format
-		_, file, line, _ := runtime.Caller(2)
-		fileList := strings.Split(file, "/")
-		r = fmt.Sprintf("[%s:%d] %s", fileList[len(fileList)-1], line, format)
+
+// SetLevel reconfigures the effective level for this logger. On the root logger
+// (no override chain) it updates the level every derived logger falls back to
+// once it's consulted via GetLevel/IsLevelEnabled, unless that branch has set its
+// own override. On a derived logger it instead sets that logger's own override,
+// which affects only this branch and leaves its parent and siblings untouched.
+//
+// The underlying zap core's AtomicLevel (l.atom) is deliberately left alone here:
+// it's shared by every logger derived from the same root (see derive()), so
+// reconfiguring it from SetLevel would only ever let branches get *more*
+// restrictive than each other, never less — a child can't turn on Debug logging
+// for itself if the root is at Info, because zap's own Core.Check would still
+// drop the entry before IsLevelEnabled is ever consulted. InitLogger instead pins
+// the core to its most permissive level once, and IsLevelEnabled is the only
+// thing that decides whether a given branch actually emits.
+func (l MyLogger) SetLevel(level Level) {
+	if l.override == nil {
+		if l.level != nil {
+			atomic.StoreInt32(l.level, int32(level))
+		}
+		return
 	}
-	return
+	atomic.StoreInt32(l.override, int32(level))
+}
+func (l MyLogger) GetLevel() Level {
+	if l.override != nil {
+		if v := atomic.LoadInt32(l.override); v != noOverride {
+			return Level(v)
+		}
+	}
+	if l.parent != nil {
+		return l.parent.GetLevel()
+	}
+	if l.level == nil {
+		return InfoLevel
+	}
+	return Level(atomic.LoadInt32(l.level))
+}
+
+func (l MyLogger) noFatalsEnabled() bool {
+	return l.noFatals != nil && atomic.LoadInt32(l.noFatals) != 0
 }
-func (l MyLogger) appendCodeInfo(args []interface{}) (r []interface{}) {
-	if l.codeLine {
-		r = args
-		_, file, line, _ := runtime.Caller(2)
-		fileList := strings.Split(file, "/")
-		args = append([]interface{}{fmt.Sprintf("[%s:%d] ", fileList[len(fileList)-1], line)}, args)
+func (l MyLogger) noPanicsEnabled() bool {
+	return l.noPanics != nil && atomic.LoadInt32(l.noPanics) != 0
+}
+
+// NoFatals toggles whether Fatal/Fatalf on this logger actually call os.Exit(1).
+// When enabled they're rewritten to Error with a "[FATAL BYPASSED]" prefix instead,
+// so unit tests can exercise error paths without tearing down the test binary.
+func (l MyLogger) NoFatals(enabled bool) {
+	if l.noFatals == nil {
+		return
 	}
-	return
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(l.noFatals, v)
 }
-func addCodeInfo(format string) (r string) {
-	r = format
-	_, file, line, _ := runtime.Caller(2)
-	fileList := strings.Split(file, "/")
-	r = fmt.Sprintf("[%s:%d] %s", fileList[len(fileList)-1], line, format)
-	return
+
+// NoPanics toggles whether Panic/Panicf on this logger actually panic. When
+// enabled they're rewritten to Warn with a "[PANIC BYPASSED]" prefix instead.
+func (l MyLogger) NoPanics(enabled bool) {
+	if l.noPanics == nil {
+		return
+	}
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(l.noPanics, v)
 }
-func appendCodeInfo(args []interface{}) (r []interface{}) {
-	r = args
-	_, file, line, _ := runtime.Caller(2)
-	fileList := strings.Split(file, "/")
-	args = append([]interface{}{fmt.Sprintf("[%s:%d] ", fileList[len(fileList)-1], line)}, args)
-	return
+
+// WithNoFatals returns a derived Logger with NoFatals(true) already applied.
+func (l MyLogger) WithNoFatals() Logger {
+	d := l.derive()
+	d.NoFatals(true)
+	return d
+}
+
+// WithNoPanics returns a derived Logger with NoPanics(true) already applied.
+func (l MyLogger) WithNoPanics() Logger {
+	d := l.derive()
+	d.NoPanics(true)
+	return d
+}
+
+// IsLevelEnabled reports whether a message at level would actually be emitted by
+// this specific logger. It's checked against GetLevel() rather than the shared zap
+// core: the core's AtomicLevel is one knob for the whole logger tree, but a
+// derived logger may have its own override (see SetLevel), so the core alone
+// can't tell whether this particular branch would emit. Trace has no zapcore
+// equivalent (it's folded into zapcore.DebugLevel alongside DebugLevel) so it's
+// only enabled when GetLevel() is exactly TraceLevel; every other level is
+// enabled when it is at least as severe as GetLevel() (our Level scale increases
+// with verbosity, so "at least as severe" means numerically <=).
+func (l MyLogger) IsLevelEnabled(level Level) bool {
+	if !isInit {
+		return true
+	}
+	if level == TraceLevel {
+		return l.GetLevel() == TraceLevel
+	}
+	return level <= l.GetLevel()
+}
+func (l MyLogger) IsTraceEnabled() bool { return l.IsLevelEnabled(TraceLevel) }
+func (l MyLogger) IsDebugEnabled() bool { return l.IsLevelEnabled(DebugLevel) }
+func (l MyLogger) IsInfoEnabled() bool  { return l.IsLevelEnabled(InfoLevel) }
+func (l MyLogger) IsWarnEnabled() bool  { return l.IsLevelEnabled(WarnLevel) }
+func (l MyLogger) IsErrorEnabled() bool { return l.IsLevelEnabled(ErrorLevel) }
+
+// Log dispatches to the sugared method matching level, letting callers pick the
+// level dynamically instead of calling Debug/Info/... directly. It goes through
+// WithCallerSkip(1) because this switch adds one more stack frame than calling
+// Debug/Info/... directly does, and %caller should still point at Log's caller.
+func (l MyLogger) Log(level Level, args ...interface{}) {
+	l = l.WithCallerSkip(1).(MyLogger)
+	switch level {
+	case PanicLevel:
+		l.Panic(args...)
+	case FatalLevel:
+		l.Fatal(args...)
+	case ErrorLevel:
+		l.Error(args...)
+	case WarnLevel:
+		l.Warn(args...)
+	case InfoLevel:
+		l.Info(args...)
+	case DebugLevel:
+		l.Debug(args...)
+	case TraceLevel:
+		l.Trace(args...)
+	}
+}
+
+// Logf is the formatted counterpart of Log; see its doc comment for the
+// caller-skip rationale.
+func (l MyLogger) Logf(level Level, format string, args ...interface{}) {
+	l = l.WithCallerSkip(1).(MyLogger)
+	switch level {
+	case PanicLevel:
+		l.Panicf(format, args...)
+	case FatalLevel:
+		l.Fatalf(format, args...)
+	case ErrorLevel:
+		l.Errorf(format, args...)
+	case WarnLevel:
+		l.Warnf(format, args...)
+	case InfoLevel:
+		l.Infof(format, args...)
+	case DebugLevel:
+		l.Debugf(format, args...)
+	case TraceLevel:
+		l.Tracef(format, args...)
+	}
+}
+
+func (l MyLogger) Print(args ...interface{}) {
+	if isInit {
+		l.log.Sugar().Info(args...)
+	} else {
+		fmt.Println(args...)
+	}
 }
 func (l MyLogger) Printf(format string, args ...interface{}) {
 	if isInit {
-		l.log.Sugar().Infof(l.addCodeInfo(format), args...)
+		l.log.Sugar().Infof(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Println(args ...interface{}) {
 	if isInit {
-		l.log.Sugar().Info(l.appendCodeInfo(args)...)
+		l.log.Sugar().Info(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Trace(args ...interface{}) {
-	if isInit {
-		if l.level == TraceLevel {
-			l.log.Sugar().Debug(l.appendCodeInfo(args)...)
-		}
-	} else {
+	if !isInit {
 		fmt.Println(args...)
+		return
+	}
+	if l.IsLevelEnabled(TraceLevel) {
+		l.log.Sugar().Debug(args...)
 	}
 }
 func (l MyLogger) Tracef(format string, args ...interface{}) {
-	if isInit {
-		if l.level == TraceLevel {
-			l.log.Sugar().Debugf(l.addCodeInfo(format), args...)
-		}
-	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+	if !isInit {
+		fmt.Printf(format+"\r\n", args...)
+		return
+	}
+	if l.IsLevelEnabled(TraceLevel) {
+		l.log.Sugar().Debugf(format, args...)
 	}
 }
 
 func (l MyLogger) Debug(args ...interface{}) {
+	if !l.IsLevelEnabled(DebugLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Debug(l.appendCodeInfo(args)...)
+		l.log.Sugar().Debug(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Debugf(format string, args ...interface{}) {
+	if !l.IsLevelEnabled(DebugLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Debugf(l.addCodeInfo(format), args...)
+		l.log.Sugar().Debugf(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Info(args ...interface{}) {
+	if !l.IsLevelEnabled(InfoLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Info(l.appendCodeInfo(args)...)
+		l.log.Sugar().Info(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Infof(format string, args ...interface{}) {
+	if !l.IsLevelEnabled(InfoLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Infof(l.addCodeInfo(format), args...)
+		l.log.Sugar().Infof(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Warn(args ...interface{}) {
+	if !l.IsLevelEnabled(WarnLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Warn(l.appendCodeInfo(args)...)
+		l.log.Sugar().Warn(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Warnf(format string, args ...interface{}) {
+	if !l.IsLevelEnabled(WarnLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Warnf(l.addCodeInfo(format), args...)
+		l.log.Sugar().Warnf(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Panic(args ...interface{}) {
+	if l.noPanicsEnabled() {
+		l.WithCallerSkip(1).Warn(append([]interface{}{"[PANIC BYPASSED]"}, args...)...)
+		return
+	}
 	if isInit {
-		l.log.Sugar().Panic(l.appendCodeInfo(args)...)
+		l.log.Sugar().Panic(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Panicf(format string, args ...interface{}) {
+	if l.noPanicsEnabled() {
+		l.WithCallerSkip(1).Warnf("[PANIC BYPASSED] "+format, args...)
+		return
+	}
 	if isInit {
-		l.log.Sugar().Panicf(l.addCodeInfo(format), args...)
+		l.log.Sugar().Panicf(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Fatal(args ...interface{}) {
+	if l.noFatalsEnabled() {
+		l.WithCallerSkip(1).Error(append([]interface{}{"[FATAL BYPASSED]"}, args...)...)
+		return
+	}
 	if isInit {
-		l.log.Sugar().Fatal(l.appendCodeInfo(args)...)
+		l.log.Sugar().Fatal(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Error(args ...interface{}) {
+	if !l.IsLevelEnabled(ErrorLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Error(l.appendCodeInfo(args)...)
+		l.log.Sugar().Error(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func (l MyLogger) Errorf(format string, args ...interface{}) {
+	if !l.IsLevelEnabled(ErrorLevel) {
+		return
+	}
 	if isInit {
-		l.log.Sugar().Errorf(l.addCodeInfo(format), args...)
+		l.log.Sugar().Errorf(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) Fatalf(format string, args ...interface{}) {
+	if l.noFatalsEnabled() {
+		l.WithCallerSkip(1).Errorf("[FATAL BYPASSED] "+format, args...)
+		return
+	}
 	if isInit {
-		l.log.Sugar().Fatalf(l.addCodeInfo(format), args...)
+		l.log.Sugar().Fatalf(format, args...)
 	} else {
-		fmt.Printf(l.addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func (l MyLogger) WithPrefix(prefix string) (log Logger) {
-	log = MyLogger{
-		log:      l.log.Named(prefix),
-		level:    l.level,
-		prefix:   append(l.prefix, prefix),
-		fields:   l.fields,
-		codeLine: l.codeLine,
-	}
+	d := l.derive()
+	d.log = l.log.Named(prefix)
+	d.prefix = append(l.prefix, prefix)
+	log = d
 	return
 }
 func (l MyLogger) Prefix() string {
@@ -296,46 +568,259 @@ func (l MyLogger) Fields() Fields {
 	}
 	return l.fields
 }
+// ContextExtractor pulls structured fields (trace id, span id, tenant, ...) out of
+// a request-scoped context.Context so WithContext/*Ctx can inject them as fields
+// without every call site having to WithFields manually.
+type ContextExtractor func(ctx context.Context) Fields
+
+var (
+	contextExtractorMu sync.RWMutex
+	contextExtractor   ContextExtractor
+)
+
+// RegisterContextExtractor installs the extractor consulted by WithContext and the
+// *Ctx logging methods. Passing nil disables context field extraction. Safe to
+// call concurrently with logging, same as RegisterHook.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorMu.Lock()
+	contextExtractor = extractor
+	contextExtractorMu.Unlock()
+}
+
+func getContextExtractor() ContextExtractor {
+	contextExtractorMu.RLock()
+	defer contextExtractorMu.RUnlock()
+	return contextExtractor
+}
+
+// OTelContextExtractor reads the active OpenTelemetry span out of ctx and exposes
+// its trace/span IDs as fields. Pass it to InitLogger or RegisterContextExtractor
+// for services that already propagate an OTel span via context.
+func OTelContextExtractor(ctx context.Context) Fields {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return Fields{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
+
+func (l MyLogger) WithContext(ctx context.Context) (log Logger) {
+	log = l
+	extractor := getContextExtractor()
+	if extractor == nil {
+		return
+	}
+	fields := extractor(ctx)
+	if len(fields) == 0 {
+		return
+	}
+	return l.WithFields(fields)
+}
+func (l MyLogger) TraceCtx(ctx context.Context, args ...interface{}) {
+	l.WithContext(ctx).WithCallerSkip(1).Trace(args...)
+}
+func (l MyLogger) DebugCtx(ctx context.Context, args ...interface{}) {
+	l.WithContext(ctx).WithCallerSkip(1).Debug(args...)
+}
+func (l MyLogger) InfoCtx(ctx context.Context, args ...interface{}) {
+	l.WithContext(ctx).WithCallerSkip(1).Info(args...)
+}
+func (l MyLogger) WarnCtx(ctx context.Context, args ...interface{}) {
+	l.WithContext(ctx).WithCallerSkip(1).Warn(args...)
+}
+func (l MyLogger) ErrorCtx(ctx context.Context, args ...interface{}) {
+	l.WithContext(ctx).WithCallerSkip(1).Error(args...)
+}
+
 func (l MyLogger) WithFields(fields Fields) (log Logger) {
 	fs := make([]zap.Field, 0)
 	for k, v := range fields {
 		fs = append(fs, zap.Any(k, v))
 	}
-	log = MyLogger{
-		log:      l.log.With(fs...),
-		level:    l.level,
-		prefix:   l.prefix,
-		fields:   fields,
-		codeLine: l.codeLine,
-	}
+	d := l.derive()
+	d.log = l.log.With(fs...)
+	d.fields = fields
+	log = d
 	return
 }
 
-func (l MyLogger) SetLevel(level Level) {
-	//can't change level
-	return
-}
-func (l MyLogger) GetLevel() Level {
-	return l.level
-}
 func (l MyLogger) Section() (s string) {
 	return
 }
 func (l MyLogger) WithSection(sec string) (log Logger) {
-	log = MyLogger{
-		log:      l.log.With(zap.String("section", sec)),
-		level:    l.level,
-		prefix:   l.prefix,
-		fields:   l.fields,
-		codeLine: l.codeLine,
-	}
+	d := l.derive()
+	d.log = l.log.With(zap.String("section", sec))
+	log = d
 	return
 }
 
+// WithCallerSkip returns a derived Logger whose reported caller is n frames
+// higher up the stack than this one's. Use it from a wrapper package (e.g. a
+// project's own pkg/log re-export of this package) to correct for the extra
+// frame(s) the wrapper itself adds.
+func (l MyLogger) WithCallerSkip(n int) Logger {
+	d := l.derive()
+	if l.log != nil {
+		d.log = l.log.WithOptions(zap.AddCallerSkip(n))
+	}
+	return d
+}
+
 func GetMyLogger() MyLogger {
 	return mylog
 }
 
+// hookQueueSize bounds the number of pending HookEntry values the worker hasn't
+// drained yet. Once full, new entries are dropped rather than blocking the write
+// path; hookDrops counts how many.
+const hookQueueSize = 1024
+
+// Hook receives entries whose level is one of its declared Levels() and ships them to
+// an external sink (error tracker, syslog, ...). Fire runs on the shared hook
+// worker goroutine, never on the caller's write path, but it should still avoid
+// doing anything slow enough to back up behind other hooks.
+type Hook interface {
+	Levels() []Level
+	Fire(entry HookEntry) error
+}
+
+// HookEntry is everything a Hook needs about one emitted log line.
+type HookEntry struct {
+	Level     Level
+	Message   string
+	Timestamp time.Time
+	Prefix    string
+	Section   string
+	Fields    Fields
+	File      string
+	Line      int
+	Stack     string
+}
+
+var (
+	hookMu    sync.RWMutex
+	hooks     []Hook
+	hookQueue = make(chan HookEntry, hookQueueSize)
+	hookDrops uint64
+)
+
+func init() {
+	go runHooks()
+}
+
+func runHooks() {
+	for entry := range hookQueue {
+		hookMu.RLock()
+		active := hooks
+		hookMu.RUnlock()
+		for _, h := range active {
+			if !hookWantsLevel(h, entry.Level) {
+				continue
+			}
+			if err := h.Fire(entry); err != nil {
+				log.Printf("logger: hook failed: %v", err)
+			}
+		}
+	}
+}
+
+func hookWantsLevel(h Hook, level Level) bool {
+	for _, l := range h.Levels() {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterHook adds a Hook that is fanned out to asynchronously for every emitted
+// log entry matching one of its declared Levels(). Entries are dropped (and
+// counted in HookDrops) if the worker falls behind rather than blocking callers.
+func RegisterHook(hook Hook) {
+	hookMu.Lock()
+	hooks = append(hooks, hook)
+	hookMu.Unlock()
+}
+
+// RegisterHook adds a Hook, same as the package-level RegisterHook.
+func (l MyLogger) RegisterHook(hook Hook) {
+	RegisterHook(hook)
+}
+
+// HookDrops returns the number of hook entries dropped so far because the worker
+// queue was full, a basic backpressure metric operators can scrape or log.
+func HookDrops() uint64 {
+	return atomic.LoadUint64(&hookDrops)
+}
+
+func dispatchHook(entry HookEntry) {
+	hookMu.RLock()
+	n := len(hooks)
+	hookMu.RUnlock()
+	if n == 0 {
+		return
+	}
+	select {
+	case hookQueue <- entry:
+	default:
+		atomic.AddUint64(&hookDrops, 1)
+	}
+}
+
+func fieldsFromZap(fs []zapcore.Field) Fields {
+	if len(fs) == 0 {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fs {
+		f.AddTo(enc)
+	}
+	return Fields(enc.Fields)
+}
+
+// hookedCore wraps a zapcore.Core and fans every entry it writes out to the
+// registered hooks, without affecting what the wrapped core itself does.
+type hookedCore struct {
+	zapcore.Core
+	// fields accumulated via With, so hooks see them alongside per-call fields.
+	fields []zapcore.Field
+}
+
+func (c hookedCore) With(fields []zapcore.Field) zapcore.Core {
+	acc := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return hookedCore{Core: c.Core.With(fields), fields: acc}
+}
+
+func (c hookedCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c hookedCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	fs := fieldsFromZap(append(c.fields[:len(c.fields):len(c.fields)], fields...))
+	section, _ := fs["section"].(string)
+	if section != "" {
+		delete(fs, "section")
+	}
+	dispatchHook(HookEntry{
+		Level:     zapToLevel(entry.Level),
+		Message:   entry.Message,
+		Timestamp: entry.Time,
+		Prefix:    entry.LoggerName,
+		Section:   section,
+		Fields:    fs,
+		File:      entry.Caller.File,
+		Line:      entry.Caller.Line,
+		Stack:     entry.Stack,
+	})
+	return c.Core.Write(entry, fields)
+}
+
 type LoggerConfig struct {
 	Level          string `json:"log_level"`
 	Path           string `json:"log_path"`
@@ -345,9 +830,53 @@ type LoggerConfig struct {
 	MaxAge         int    `json:"log_max_age"`
 	EnableCompress int    `json:"log_compress"`
 	CodeLine       int    `json:"log_code_line"`
+	// Format selects the encoder: "console" (default), "json" or "logfmt".
+	Format string `json:"log_format"`
+	// Color enables colored level names on the stderr sink that ConsoleOutput
+	// adds; the file sink is never colorized, whatever its Format. On Windows it
+	// also turns on ANSI escape processing for stdout/stderr so the colors
+	// actually render in cmd.exe/PowerShell builds prior to 10 1909.
+	Color bool `json:"log_color"`
+	// ConsoleOutput tees a second, console-encoded sink to stderr (colorized when
+	// Color is set) in addition to the configured file sink, so operators get
+	// pretty logs on the terminal while still writing (e.g.) JSON to disk.
+	ConsoleOutput bool `json:"log_console_output"`
+	// CallerSkip adds extra stack frames to skip before reporting the caller, for
+	// projects that wrap this package (e.g. their own pkg/log) and would
+	// otherwise always report that wrapper's call site instead of their own
+	// caller's. Equivalent to calling WithCallerSkip(CallerSkip) on the root
+	// logger.
+	CallerSkip int `json:"log_caller_skip"`
+}
+
+// baseCallerSkip accounts for the one frame every MyLogger method and
+// package-level function (Info, Debug, ...) adds between the caller and the
+// zap.SugaredLogger call that actually reports %caller.
+const baseCallerSkip = 1
+
+// buildEncoder returns the zapcore.Encoder for format ("console", "json" or
+// "logfmt", defaulting to "console"), applying cfg and optionally colorizing
+// level names.
+func buildEncoder(cfg zapcore.EncoderConfig, format string, color bool) zapcore.Encoder {
+	if color {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	switch strings.ToLower(format) {
+	case "json":
+		return zapcore.NewJSONEncoder(cfg)
+	case "logfmt":
+		return zaplogfmt.NewEncoder(cfg)
+	default:
+		return zapcore.NewConsoleEncoder(cfg)
+	}
 }
 
-func InitLogger(logger LoggerConfig) {
+// InitLogger builds and installs the package-level logger. extractor is optional;
+// if given, it's installed the same as calling RegisterContextExtractor afterwards.
+func InitLogger(logger LoggerConfig, extractor ...ContextExtractor) {
+	if len(extractor) > 0 {
+		RegisterContextExtractor(extractor[0])
+	}
 	logPathAccessabel := LogPathExists(logger.Path)
 	logPath := "./"
 
@@ -406,37 +935,52 @@ func InitLogger(logger LoggerConfig) {
 		EncodeDuration: zapcore.StringDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
-	level := zap.InfoLevel
 	myLevel := TraceLevel
 	switch strings.ToLower(logger.Level) {
 	case "trace":
 		myLevel = TraceLevel
-		level = zap.DebugLevel
 	case "debug":
 		myLevel = DebugLevel
-		level = zap.DebugLevel
 	case "warn":
 		myLevel = WarnLevel
-		level = zap.WarnLevel
 	case "info":
 		myLevel = InfoLevel
-		level = zap.InfoLevel
 	case "error":
 		myLevel = ErrorLevel
-		level = zap.ErrorLevel
 	default:
-		level = zap.InfoLevel
+		myLevel = InfoLevel
+	}
+	// The core itself is pinned to the most permissive zap level so it never
+	// filters anything out from under a derived logger's own override (see
+	// SetLevel); myLevel still becomes the root's nominal level below, and every
+	// actual gating decision goes through IsLevelEnabled instead.
+	atom := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	if logger.Color {
+		enableANSI()
+	}
+	// Color is a terminal affordance only: the file sink is never colorized, even
+	// when Format is "console", so rotated log files on disk stay free of ANSI
+	// escapes regardless of whether ConsoleOutput is also enabled.
+	fileEncoder := buildEncoder(encoderCfg, logger.Format, false)
+	cores := []zapcore.Core{zapcore.NewCore(fileEncoder, w, atom)}
+	if logger.ConsoleOutput {
+		consoleEncoder := buildEncoder(encoderCfg, "console", logger.Color)
+		cores = append(cores, zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stderr), atom))
+	}
+	core := hookedCore{Core: zapcore.NewTee(cores...)}
+	level := new(int32)
+	atomic.StoreInt32(level, int32(myLevel))
+	var opts []zap.Option
+	if needCodeLine {
+		opts = append(opts, zap.AddCaller(), zap.AddCallerSkip(baseCallerSkip+logger.CallerSkip))
 	}
-	core := zapcore.NewCore(
-		zapcore.NewConsoleEncoder(encoderCfg),
-		w,
-		level,
-	)
 	mylog = MyLogger{
-		log:      zap.New(core),
-		level:    myLevel,
+		log:      zap.New(core, opts...),
+		atom:     atom,
+		level:    level,
 		fields:   make(map[string]interface{}),
-		codeLine: needCodeLine,
+		noFatals: new(int32),
+		noPanics: new(int32),
 	}
 	isInit = true
 }
@@ -449,128 +993,226 @@ func LogPathExists(path string) bool {
 	return false
 }
 
+// SetLevel reconfigures the running logger's minimum level at runtime, including
+// for every logger previously derived via WithPrefix/WithFields/WithSection,
+// except branches that have set their own level via SetLevel.
+func SetLevel(level Level) {
+	if isInit {
+		mylog.SetLevel(level)
+	}
+}
+
+// GetLevel returns the running logger's current level.
+func GetLevel() Level {
+	if isInit {
+		return mylog.GetLevel()
+	}
+	return InfoLevel
+}
+
+// NoFatals toggles whether the package-level Fatal/Fatalf actually call os.Exit(1).
+func NoFatals(enabled bool) { mylog.NoFatals(enabled) }
+
+// NoPanics toggles whether the package-level Panic/Panicf actually panic.
+func NoPanics(enabled bool) { mylog.NoPanics(enabled) }
+
+// IsLevelEnabled reports whether a message at level would actually be emitted,
+// so callers can gate expensive fmt.Sprintf/JSON building around log statements.
+func IsLevelEnabled(level Level) bool {
+	return mylog.IsLevelEnabled(level)
+}
+func IsTraceEnabled() bool { return IsLevelEnabled(TraceLevel) }
+func IsDebugEnabled() bool { return IsLevelEnabled(DebugLevel) }
+func IsInfoEnabled() bool  { return IsLevelEnabled(InfoLevel) }
+func IsWarnEnabled() bool  { return IsLevelEnabled(WarnLevel) }
+func IsErrorEnabled() bool { return IsLevelEnabled(ErrorLevel) }
+
+// Log dispatches to the package-level logger's method matching level.
+func Log(level Level, args ...interface{}) {
+	mylog.WithCallerSkip(1).Log(level, args...)
+}
+func Logf(level Level, format string, args ...interface{}) {
+	mylog.WithCallerSkip(1).Logf(level, format, args...)
+}
+
+func TraceCtx(ctx context.Context, args ...interface{}) {
+	mylog.WithCallerSkip(1).TraceCtx(ctx, args...)
+}
+func DebugCtx(ctx context.Context, args ...interface{}) {
+	mylog.WithCallerSkip(1).DebugCtx(ctx, args...)
+}
+func InfoCtx(ctx context.Context, args ...interface{}) {
+	mylog.WithCallerSkip(1).InfoCtx(ctx, args...)
+}
+func WarnCtx(ctx context.Context, args ...interface{}) {
+	mylog.WithCallerSkip(1).WarnCtx(ctx, args...)
+}
+func ErrorCtx(ctx context.Context, args ...interface{}) {
+	mylog.WithCallerSkip(1).ErrorCtx(ctx, args...)
+}
+
 func Print(args ...interface{}) {
 	if isInit {
-		mylog.log.Sugar().Info(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Info(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Printf(format string, args ...interface{}) {
 	if isInit {
-		mylog.log.Sugar().Infof(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Infof(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Println(args ...interface{}) {
 	if isInit {
-		mylog.log.Sugar().Info(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Info(args...)
 	} else {
 		fmt.Print(args...)
 	}
 
 }
 func Trace(args ...interface{}) {
-	if isInit {
-		if mylog.level == TraceLevel {
-			mylog.log.Sugar().Debug(mylog.appendCodeInfo(args)...)
-		}
-	} else {
+	if !isInit {
 		fmt.Println(args...)
+		return
+	}
+	if mylog.IsLevelEnabled(TraceLevel) {
+		mylog.log.Sugar().Debug(args...)
 	}
 }
 func Tracef(format string, args ...interface{}) {
-	if isInit {
-		if mylog.level == TraceLevel {
-			mylog.log.Sugar().Debugf(mylog.addCodeInfo(format), args...)
-		}
-	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+	if !isInit {
+		fmt.Printf(format+"\r\n", args...)
+		return
+	}
+	if mylog.IsLevelEnabled(TraceLevel) {
+		mylog.log.Sugar().Debugf(format, args...)
 	}
 }
 
 func Debug(args ...interface{}) {
+	if !mylog.IsLevelEnabled(DebugLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Debug(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Debug(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Debugf(format string, args ...interface{}) {
+	if !mylog.IsLevelEnabled(DebugLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Debugf(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Debugf(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Info(args ...interface{}) {
+	if !mylog.IsLevelEnabled(InfoLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Info(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Info(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Infof(format string, args ...interface{}) {
+	if !mylog.IsLevelEnabled(InfoLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Infof(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Infof(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Warn(args ...interface{}) {
+	if !mylog.IsLevelEnabled(WarnLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Warn(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Warn(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Warnf(format string, args ...interface{}) {
+	if !mylog.IsLevelEnabled(WarnLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Warnf(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Warnf(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Panic(args ...interface{}) {
+	if mylog.noPanicsEnabled() {
+		mylog.WithCallerSkip(1).Warn(append([]interface{}{"[PANIC BYPASSED]"}, args...)...)
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Panic(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Panic(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Panicf(format string, args ...interface{}) {
+	if mylog.noPanicsEnabled() {
+		mylog.WithCallerSkip(1).Warnf("[PANIC BYPASSED] "+format, args...)
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Panicf(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Panicf(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Error(args ...interface{}) {
+	if !mylog.IsLevelEnabled(ErrorLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Error(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Error(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Errorf(format string, args ...interface{}) {
+	if !mylog.IsLevelEnabled(ErrorLevel) {
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Errorf(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Errorf(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
 func Fatal(args ...interface{}) {
+	if mylog.noFatalsEnabled() {
+		mylog.WithCallerSkip(1).Error(append([]interface{}{"[FATAL BYPASSED]"}, args...)...)
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Fatal(mylog.appendCodeInfo(args)...)
+		mylog.log.Sugar().Fatal(args...)
 	} else {
 		fmt.Println(args...)
 	}
 }
 func Fatalf(format string, args ...interface{}) {
+	if mylog.noFatalsEnabled() {
+		mylog.WithCallerSkip(1).Errorf("[FATAL BYPASSED] "+format, args...)
+		return
+	}
 	if isInit {
-		mylog.log.Sugar().Fatalf(mylog.addCodeInfo(format), args...)
+		mylog.log.Sugar().Fatalf(format, args...)
 	} else {
-		fmt.Printf(addCodeInfo(format)+"\r\n", args...)
+		fmt.Printf(format+"\r\n", args...)
 	}
 }
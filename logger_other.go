@@ -0,0 +1,7 @@
+//go:build !windows
+
+package logger
+
+// enableANSI is a no-op outside Windows; Unix terminals already interpret ANSI
+// color escapes natively.
+func enableANSI() {}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newBufferedLogger builds a MyLogger wired the same way InitLogger does, except
+// the core writes to buf instead of a lumberjack file, so tests can assert on
+// emitted lines without touching disk. It also flips the package-level isInit
+// flag (restored via t.Cleanup) since every emit method short-circuits on it.
+func newBufferedLogger(t *testing.T, buf *bytes.Buffer, startLevel Level) MyLogger {
+	t.Helper()
+	prevIsInit, prevMylog := isInit, mylog
+	t.Cleanup(func() {
+		isInit, mylog = prevIsInit, prevMylog
+	})
+	isInit = true
+
+	encoderCfg := zapcore.EncoderConfig{
+		MessageKey:  "M",
+		LevelKey:    "L",
+		EncodeLevel: zapcore.CapitalLevelEncoder,
+	}
+	// Pinned to the most permissive zap level, same as InitLogger: gating is
+	// IsLevelEnabled's job, not the shared core's.
+	atom := zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(encoderCfg), zapcore.AddSync(buf), atom)
+	level := new(int32)
+	atomic.StoreInt32(level, int32(startLevel))
+	return MyLogger{
+		log:      zap.New(core),
+		atom:     atom,
+		level:    level,
+		fields:   make(Fields),
+		noFatals: new(int32),
+		noPanics: new(int32),
+	}
+}
+
+func TestSetLevelFlipsEmissionAtRuntime(t *testing.T) {
+	var buf bytes.Buffer
+	l := newBufferedLogger(t, &buf, DebugLevel)
+
+	l.Debug("debug one")
+	l.Error("error one")
+	l.SetLevel(ErrorLevel)
+	buf.Reset()
+
+	l.Debug("debug two")
+	l.Error("error two")
+
+	out := buf.String()
+	if strings.Contains(out, "debug two") {
+		t.Fatalf("expected Debug to be suppressed after SetLevel(ErrorLevel), got: %q", out)
+	}
+	if !strings.Contains(out, "error two") {
+		t.Fatalf("expected Error to still be emitted after SetLevel(ErrorLevel), got: %q", out)
+	}
+}
+
+func TestDerivedLoggerLevelOverrideIsolatesSilencing(t *testing.T) {
+	var buf bytes.Buffer
+	parent := newBufferedLogger(t, &buf, DebugLevel)
+	child := parent.WithPrefix("child")
+	child.SetLevel(ErrorLevel)
+
+	child.Debug("child debug")
+	parent.Debug("parent debug")
+
+	out := buf.String()
+	if strings.Contains(out, "child debug") {
+		t.Fatalf("expected child's Debug to be silenced by its own override, got: %q", out)
+	}
+	if !strings.Contains(out, "parent debug") {
+		t.Fatalf("expected parent to keep emitting Debug while child is silenced, got: %q", out)
+	}
+	if parent.GetLevel() != DebugLevel {
+		t.Fatalf("expected parent level to be unaffected by child's override, got %v", parent.GetLevel())
+	}
+}
+
+func TestDerivedLoggerCanWidenAboveRootLevel(t *testing.T) {
+	var buf bytes.Buffer
+	parent := newBufferedLogger(t, &buf, InfoLevel)
+	child := parent.WithPrefix("child")
+	child.SetLevel(DebugLevel)
+
+	child.Debug("child debug")
+	parent.Debug("parent debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "child debug") {
+		t.Fatalf("expected child's own override to turn on Debug even though root is at Info, got: %q", out)
+	}
+	if strings.Contains(out, "parent debug") {
+		t.Fatalf("expected parent to stay silenced at Info while child opts into Debug, got: %q", out)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+// enableANSI turns on ENABLE_VIRTUAL_TERMINAL_PROCESSING for stdout/stderr so
+// ANSI color escapes render instead of printing as literal garbage in cmd.exe and
+// PowerShell builds prior to Windows 10 1909.
+func enableANSI() {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		handle := syscall.Handle(f.Fd())
+		var mode uint32
+		ret, _, _ := procGetConsoleMode.Call(uintptr(handle), uintptr(unsafe.Pointer(&mode)))
+		if ret == 0 {
+			continue
+		}
+		procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	}
+}